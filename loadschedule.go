@@ -0,0 +1,101 @@
+package ghz
+
+import (
+	"math"
+	"time"
+)
+
+// LoadSchedule computes the target QPS for a run at a given point in time,
+// allowing the load to ramp, step or vary instead of staying flat
+type LoadSchedule interface {
+	// QPS returns the target queries per second at the given elapsed duration
+	// since the start of the run
+	QPS(elapsed time.Duration) uint
+}
+
+// ConstantLoad is a LoadSchedule that keeps the QPS fixed for the whole run.
+// This is the default, flat-rate behavior
+type ConstantLoad struct {
+	QPSValue uint
+}
+
+// QPS returns the fixed QPS regardless of elapsed time
+func (l ConstantLoad) QPS(elapsed time.Duration) uint {
+	return l.QPSValue
+}
+
+// LinearRampLoad is a LoadSchedule that ramps the QPS linearly from StartQPS to
+// EndQPS over Duration, then holds at EndQPS
+type LinearRampLoad struct {
+	StartQPS uint
+	EndQPS   uint
+	Duration time.Duration
+}
+
+// QPS returns the linearly interpolated QPS for the given elapsed time
+func (l LinearRampLoad) QPS(elapsed time.Duration) uint {
+	if l.Duration <= 0 || elapsed >= l.Duration {
+		return l.EndQPS
+	}
+
+	progress := float64(elapsed) / float64(l.Duration)
+	start := float64(l.StartQPS)
+	end := float64(l.EndQPS)
+
+	return uint(math.Round(start + (end-start)*progress))
+}
+
+// LoadStep is a single step in a StepLoad schedule
+type LoadStep struct {
+	QPS      uint
+	Duration time.Duration
+}
+
+// StepLoad is a LoadSchedule that holds each QPS in Steps for its Duration, in
+// order, before moving on to the next step
+type StepLoad struct {
+	Steps []LoadStep
+}
+
+// QPS returns the QPS of whichever step contains the given elapsed time, or the
+// last step's QPS once all steps have elapsed
+func (l StepLoad) QPS(elapsed time.Duration) uint {
+	var at time.Duration
+
+	for _, step := range l.Steps {
+		at += step.Duration
+		if elapsed < at {
+			return step.QPS
+		}
+	}
+
+	if len(l.Steps) > 0 {
+		return l.Steps[len(l.Steps)-1].QPS
+	}
+
+	return 0
+}
+
+// SineLoad is a LoadSchedule that oscillates the QPS sinusoidally around BaseQPS
+// with the given Amplitude and Period
+type SineLoad struct {
+	BaseQPS   uint
+	Amplitude uint
+	Period    time.Duration
+}
+
+// QPS returns the QPS at a point along the sine wave for the given elapsed time
+func (l SineLoad) QPS(elapsed time.Duration) uint {
+	if l.Period <= 0 {
+		return l.BaseQPS
+	}
+
+	phase := 2 * math.Pi * float64(elapsed) / float64(l.Period)
+	value := float64(l.BaseQPS) + float64(l.Amplitude)*math.Sin(phase)
+
+	if value < 0 {
+		return 0
+	}
+
+	return uint(math.Round(value))
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/bojand/hri"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
 )
 
 // RunConfig represents the request Configs
@@ -22,16 +23,24 @@ type RunConfig struct {
 	proto       string
 	importPaths []string
 	protoset    string
+	workload    []weightedCall
+	reflection  bool
 
 	// securit settings
-	cert     string
-	cname    string
-	insecure bool
+	cert           string
+	cname          string
+	insecure       bool
+	clientCert     string
+	clientKey      string
+	token          string
+	tokenCmd       string
+	rpcCredentials credentials.PerRPCCredentials
 
 	// test
-	n   int
-	c   int
-	qps int
+	n            int
+	c            int
+	qps          int
+	loadSchedule LoadSchedule
 
 	// timeouts
 	z             time.Duration
@@ -40,9 +49,24 @@ type RunConfig struct {
 	keepaliveTime time.Duration
 
 	// data
-	data     []byte
-	binary   bool
-	metadata []byte
+	data         []byte
+	binary       bool
+	metadata     []byte
+	dataTemplate string
+	dataProvider func(reqNum int) ([]byte, error)
+
+	// streaming
+	streamMessages  [][]byte
+	streamInterval  time.Duration
+	streamCallCount uint
+
+	// exporters
+	prometheusPushgatewayURL string
+	prometheusJobName        string
+	influxDBURL              string
+	influxDBDatabase         string
+	influxDBMeasurement      string
+	openMetricsOutput        io.Writer
 
 	// misc
 	name string
@@ -73,6 +97,64 @@ func WithInsecure(insec bool) Option {
 	}
 }
 
+// WithClientCertificate specifies the client certificate and key files for
+// mutual TLS authentication with the server
+func WithClientCertificate(certFile string, keyFile string) Option {
+	return func(o *RunConfig) error {
+		certFile = strings.TrimSpace(certFile)
+		keyFile = strings.TrimSpace(keyFile)
+
+		if certFile == "" || keyFile == "" {
+			return errors.New("Client certificate and key required")
+		}
+
+		o.clientCert = certFile
+		o.clientKey = keyFile
+
+		return nil
+	}
+}
+
+// WithBearerToken specifies a static OAuth2/JWT bearer token to send with every
+// call as per-RPC credentials
+func WithBearerToken(token string) Option {
+	return func(o *RunConfig) error {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			return errors.New("Token required")
+		}
+
+		o.token = token
+
+		return nil
+	}
+}
+
+// WithBearerTokenFromCommand specifies a shell command that is run to fetch (and
+// periodically refresh) the OAuth2/JWT bearer token sent with every call
+func WithBearerTokenFromCommand(cmd string) Option {
+	return func(o *RunConfig) error {
+		cmd = strings.TrimSpace(cmd)
+		if cmd == "" {
+			return errors.New("Token command required")
+		}
+
+		o.tokenCmd = cmd
+
+		return nil
+	}
+}
+
+// WithPerRPCCredentials specifies arbitrary gRPC per-RPC credentials, for
+// authentication schemes not covered by WithBearerToken
+func WithPerRPCCredentials(creds credentials.PerRPCCredentials) Option {
+	return func(o *RunConfig) error {
+		o.rpcCredentials = creds
+
+		return nil
+	}
+}
+
 // WithTotalRequests specifies the N (number of total requests) setting
 func WithTotalRequests(n uint) Option {
 	return func(o *RunConfig) error {
@@ -100,6 +182,16 @@ func WithQPS(qps uint) Option {
 	}
 }
 
+// WithLoadSchedule specifies the load schedule to follow instead of a flat QPS,
+// so the target rate can ramp, step or vary over the course of the run
+func WithLoadSchedule(schedule LoadSchedule) Option {
+	return func(o *RunConfig) error {
+		o.loadSchedule = schedule
+
+		return nil
+	}
+}
+
 // WithRunDuration specifies the Z (total test duration) option
 func WithRunDuration(z time.Duration) Option {
 	return func(o *RunConfig) error {
@@ -217,6 +309,75 @@ func WithDataFromFile(path string) Option {
 	}
 }
 
+// WithStreamMessages specifies the sequence of messages to send on a client-streaming
+// or bidi-streaming call. Each message is marshalled to JSON individually, the same
+// way WithData handles a single unary request message.
+func WithStreamMessages(messages []interface{}) Option {
+	return func(o *RunConfig) error {
+		data := make([][]byte, len(messages))
+
+		for i, m := range messages {
+			mJSON, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+
+			data[i] = mJSON
+		}
+
+		o.streamMessages = data
+
+		return nil
+	}
+}
+
+// WithStreamInterval specifies the interval to wait between sending each message
+// in a client-streaming or bidi-streaming call
+func WithStreamInterval(interval time.Duration) Option {
+	return func(o *RunConfig) error {
+		o.streamInterval = interval
+
+		return nil
+	}
+}
+
+// WithStreamCallCount specifies the number of times the stream message sequence
+// should be sent over a single client-streaming or bidi-streaming call
+func WithStreamCallCount(c uint) Option {
+	return func(o *RunConfig) error {
+		o.streamCallCount = c
+
+		return nil
+	}
+}
+
+// WithDataTemplate specifies a text/template string used to generate the request
+// payload per request. The template is evaluated with helpers .RequestNumber,
+// .WorkerID, randUUID, randInt and timestamp, so that each request can carry a
+// unique payload instead of reusing fixed data
+func WithDataTemplate(tmpl string) Option {
+	return func(o *RunConfig) error {
+		tmpl = strings.TrimSpace(tmpl)
+		if tmpl != "" {
+			o.dataTemplate = tmpl
+			o.binary = false
+		}
+
+		return nil
+	}
+}
+
+// WithDataProvider specifies a function called to generate the request payload
+// for each request number, as an alternative to WithDataTemplate
+func WithDataProvider(provider func(reqNum int) ([]byte, error)) Option {
+	return func(o *RunConfig) error {
+		o.dataProvider = provider
+		o.binary = false
+
+		return nil
+	}
+}
+
 // WithMetadataFromJSON specifies the metadata to be read from JSON string
 func WithMetadataFromJSON(md string) Option {
 	return func(o *RunConfig) error {
@@ -277,6 +438,50 @@ func WithCPUs(c uint) Option {
 	}
 }
 
+// WithPrometheusPushgateway specifies a Prometheus Pushgateway URL and job name
+// that the accumulated latency histograms and status code counters are pushed
+// to at the end of the run
+func WithPrometheusPushgateway(url string, jobName string) Option {
+	return func(o *RunConfig) error {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			return errors.New("Pushgateway URL required")
+		}
+
+		o.prometheusPushgatewayURL = url
+		o.prometheusJobName = strings.TrimSpace(jobName)
+
+		return nil
+	}
+}
+
+// WithInfluxDBOutput specifies an InfluxDB URL, database and measurement name
+// that the run's results are written to using the InfluxDB line protocol
+func WithInfluxDBOutput(url string, database string, measurement string) Option {
+	return func(o *RunConfig) error {
+		url = strings.TrimSpace(url)
+		if url == "" {
+			return errors.New("InfluxDB URL required")
+		}
+
+		o.influxDBURL = url
+		o.influxDBDatabase = strings.TrimSpace(database)
+		o.influxDBMeasurement = strings.TrimSpace(measurement)
+
+		return nil
+	}
+}
+
+// WithOpenMetricsOutput specifies a writer that the run's results are written to
+// in OpenMetrics exposition format
+func WithOpenMetricsOutput(w io.Writer) Option {
+	return func(o *RunConfig) error {
+		o.openMetricsOutput = w
+
+		return nil
+	}
+}
+
 // WithProtoFile specified proto file path and optionally import paths
 // We will automatically add the proto file path's directory and the current directory
 func WithProtoFile(proto string, importPaths []string) Option {
@@ -315,6 +520,74 @@ func WithProtoset(protoset string) Option {
 	}
 }
 
+// CallSpec describes a single call within a weighted multi-call workload
+type CallSpec struct {
+	Call     string
+	Data     interface{}
+	Metadata *map[string]string
+	Weight   uint
+}
+
+// weightedCall is the resolved, marshalled form of a CallSpec stored on RunConfig
+type weightedCall struct {
+	call     string
+	data     []byte
+	metadata []byte
+	weight   uint
+}
+
+// WithWorkload specifies a weighted mix of calls to run against the host, so that a
+// single run can exercise several RPC methods in the given proportions instead of
+// a single call
+func WithWorkload(workload []CallSpec) Option {
+	return func(o *RunConfig) error {
+		calls := make([]weightedCall, len(workload))
+
+		for i, spec := range workload {
+			call := strings.TrimSpace(spec.Call)
+			if call == "" {
+				return errors.New("Workload call required")
+			}
+
+			wc := weightedCall{call: call, weight: spec.Weight}
+
+			if spec.Data != nil {
+				dataJSON, err := json.Marshal(spec.Data)
+				if err != nil {
+					return err
+				}
+
+				wc.data = dataJSON
+			}
+
+			if spec.Metadata != nil {
+				mdJSON, err := json.Marshal(spec.Metadata)
+				if err != nil {
+					return err
+				}
+
+				wc.metadata = mdJSON
+			}
+
+			calls[i] = wc
+		}
+
+		o.workload = calls
+
+		return nil
+	}
+}
+
+// WithReflection specifies whether the proto / protoset should instead be
+// discovered at run time via the gRPC server reflection API
+func WithReflection(r bool) Option {
+	return func(o *RunConfig) error {
+		o.reflection = r
+
+		return nil
+	}
+}
+
 func newConfig(call, host string, options ...Option) (*RunConfig, error) {
 	call = strings.TrimSpace(call)
 	host = strings.TrimSpace(host)
@@ -338,7 +611,7 @@ func newConfig(call, host string, options ...Option) (*RunConfig, error) {
 		}
 	}
 
-	if c.call == "" {
+	if c.call == "" && len(c.workload) == 0 {
 		return nil, errors.New("Call required")
 	}
 
@@ -346,7 +619,7 @@ func newConfig(call, host string, options ...Option) (*RunConfig, error) {
 		return nil, errors.New("Host required")
 	}
 
-	if c.proto == "" && c.protoset == "" {
+	if c.proto == "" && c.protoset == "" && !c.reflection {
 		return nil, errors.New("Must provide proto or protoset")
 	}
 